@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// Authenticator logs a raw Vault API client in against some auth backend
+// and returns the resulting auth secret (with Auth.ClientToken populated).
+// Implementations must call client.SetToken on success.
+type Authenticator interface {
+	Login(ctx context.Context, client *vault.Client) (*vault.Secret, error)
+}
+
+// TokenAuth authenticates using a pre-existing static token.
+type TokenAuth string
+
+// Login implements Authenticator. It looks the token up against Vault so a
+// garbage or already-revoked token fails fast at construction time rather
+// than on the first unrelated call.
+func (t TokenAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	client.SetToken(string(t))
+	return lookupTokenSelf(ctx, client, string(t))
+}
+
+// lookupTokenSelf validates token against Vault and wraps it as an auth
+// secret carrying the token's real renewability and TTL, so the renewal
+// subsystem knows whether it's worth watching.
+func lookupTokenSelf(ctx context.Context, client *vault.Client, token string) (*vault.Secret, error) {
+	self, err := client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	renewable, _ := self.Data["renewable"].(bool)
+
+	var leaseDuration int
+	if ttl, ok := self.Data["ttl"].(json.Number); ok {
+		if n, err := ttl.Int64(); err == nil {
+			leaseDuration = int(n)
+		}
+	}
+
+	return &vault.Secret{Auth: &vault.SecretAuth{
+		ClientToken:   token,
+		Renewable:     renewable,
+		LeaseDuration: leaseDuration,
+	}}, nil
+}
+
+// userpassAuth authenticates against the userpass auth method.
+type userpassAuth struct {
+	username string
+	password string
+	mount    string
+}
+
+// UserpassAuth authenticates against the userpass auth method mounted at
+// mount (defaults to "userpass" when empty).
+func UserpassAuth(username, password, mount string) Authenticator {
+	if mount == "" {
+		mount = "userpass"
+	}
+	return &userpassAuth{username: username, password: password, mount: mount}
+}
+
+// Login implements Authenticator.
+func (u *userpassAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	path := fmt.Sprintf("auth/%s/login/%s", u.mount, u.username)
+	secret, err := client.Logical().WriteWithContext(ctx, path, map[string]interface{}{
+		"password": u.password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("userpass login failed: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, fmt.Errorf("userpass login at %s returned no auth info", path)
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// appRoleAuth authenticates against the approle auth method.
+type appRoleAuth struct {
+	roleID   string
+	secretID string
+}
+
+// AppRoleAuth authenticates against the approle auth method mounted at the
+// default "auth/approle" path.
+func AppRoleAuth(roleID, secretID string) Authenticator {
+	return &appRoleAuth{roleID: roleID, secretID: secretID}
+}
+
+// Login implements Authenticator.
+func (a *appRoleAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	secret, err := client.Logical().WriteWithContext(ctx, "auth/approle/login", map[string]interface{}{
+		"role_id":   a.roleID,
+		"secret_id": a.secretID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("approle login failed: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, fmt.Errorf("approle login returned no auth info")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// defaultKubernetesJWTPath is where the Kubernetes service account token is
+// projected into a pod by default.
+const defaultKubernetesJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// kubernetesAuth authenticates against the kubernetes auth method.
+type kubernetesAuth struct {
+	role    string
+	jwtPath string
+}
+
+// KubernetesAuth authenticates against the kubernetes auth method using the
+// service account JWT at jwtPath (defaults to the standard in-pod location
+// when empty).
+func KubernetesAuth(role, jwtPath string) Authenticator {
+	if jwtPath == "" {
+		jwtPath = defaultKubernetesJWTPath
+	}
+	return &kubernetesAuth{role: role, jwtPath: jwtPath}
+}
+
+// Login implements Authenticator.
+func (k *kubernetesAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	jwt, err := os.ReadFile(k.jwtPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Kubernetes service account token at %s: %w", k.jwtPath, err)
+	}
+
+	secret, err := client.Logical().WriteWithContext(ctx, "auth/kubernetes/login", map[string]interface{}{
+		"role": k.role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes login failed: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, fmt.Errorf("kubernetes login returned no auth info")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+// tokenHelperAuth authenticates using $VAULT_TOKEN, falling back to the
+// token cached by the Vault CLI at ~/.vault-token.
+type tokenHelperAuth struct{}
+
+// TokenHelperAuth authenticates using the same token resolution order as the
+// Vault CLI: the VAULT_TOKEN environment variable, then ~/.vault-token.
+func TokenHelperAuth() Authenticator {
+	return tokenHelperAuth{}
+}
+
+// Login implements Authenticator.
+func (tokenHelperAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	token := os.Getenv("VAULT_TOKEN")
+
+	if token == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			if data, rerr := os.ReadFile(filepath.Join(home, ".vault-token")); rerr == nil {
+				token = strings.TrimSpace(string(data))
+			}
+		}
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("no Vault token found in $VAULT_TOKEN or ~/.vault-token")
+	}
+
+	client.SetToken(token)
+	return lookupTokenSelf(ctx, client, token)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// RenewalEventKind identifies the kind of lifecycle event emitted by the
+// renewal subsystem.
+type RenewalEventKind string
+
+const (
+	// RenewalEventRenewed is emitted each time a tracked secret or the login
+	// token is successfully renewed.
+	RenewalEventRenewed RenewalEventKind = "renewed"
+	// RenewalEventExpired is emitted when a watcher gives up on a secret and
+	// no further renewal is possible.
+	RenewalEventExpired RenewalEventKind = "expired"
+	// RenewalEventReauthenticated is emitted after the client transparently
+	// re-authenticates following an expired login token.
+	RenewalEventReauthenticated RenewalEventKind = "reauthenticated"
+	// RenewalEventUnmanaged is emitted when StartRenewal is asked to watch a
+	// login token that Vault reports as non-renewable (e.g. a root token); no
+	// watcher is started. TrackLease emits no event for a non-renewable lease
+	// and returns an error instead.
+	RenewalEventUnmanaged RenewalEventKind = "unmanaged"
+)
+
+// RenewalEvent describes a single renewal lifecycle occurrence. Source is
+// "token" for the client's own login token, or "lease" for a secret handed
+// to TrackLease.
+type RenewalEvent struct {
+	Kind   RenewalEventKind
+	Source string
+	Err    error
+}
+
+// Events returns the channel on which renewal lifecycle events are
+// published. It is safe to call before StartRenewal; the channel is
+// created lazily and shared across StartRenewal and TrackLease.
+func (v *VaultClient) Events() <-chan RenewalEvent {
+	v.initRenewalState()
+	return v.renewalEvents
+}
+
+func (v *VaultClient) initRenewalState() {
+	v.renewalOnce.Do(func() {
+		v.renewalEvents = make(chan RenewalEvent, 16)
+	})
+}
+
+func (v *VaultClient) emit(event RenewalEvent) {
+	select {
+	case v.renewalEvents <- event:
+	default:
+		// Don't block the renewal loop on a slow or absent consumer.
+	}
+}
+
+// StartRenewal launches a background goroutine that keeps the client's
+// login token alive using a vault.LifetimeWatcher, re-authenticating via the
+// Authenticator supplied to NewVaultClientWithAuth whenever the watcher
+// terminates with a non-renewable error. It is safe to call once per
+// client; the renewal loop stops when ctx is cancelled.
+func (v *VaultClient) StartRenewal(ctx context.Context) error {
+	v.initRenewalState()
+
+	v.renewalStartOnce.Do(func() {
+		v.renewalStartErr = v.startTokenRenewal(ctx)
+	})
+
+	return v.renewalStartErr
+}
+
+func (v *VaultClient) startTokenRenewal(ctx context.Context) error {
+	if v.authSecret == nil || v.authSecret.Auth == nil {
+		return fmt.Errorf("vault: no auth secret to renew, client was not constructed with NewVaultClientWithAuth")
+	}
+
+	v.renewalCtx = ctx
+
+	go v.watchTokenLoop(ctx, v.authSecret)
+	return nil
+}
+
+func (v *VaultClient) watchTokenLoop(ctx context.Context, secret *vault.Secret) {
+	if secret.Auth == nil || !secret.Auth.Renewable {
+		v.emit(RenewalEvent{Kind: RenewalEventUnmanaged, Source: "token", Err: fmt.Errorf("vault: token is not renewable")})
+		return
+	}
+
+	watcher, err := v.client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{Secret: secret})
+	if err != nil {
+		v.emit(RenewalEvent{Kind: RenewalEventExpired, Source: "token", Err: fmt.Errorf("failed to start token watcher: %w", err)})
+		return
+	}
+
+	go watcher.Start()
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case renewal := <-watcher.RenewCh():
+			_ = renewal
+			v.emit(RenewalEvent{Kind: RenewalEventRenewed, Source: "token"})
+
+		case err := <-watcher.DoneCh():
+			// DoneCh fires both when renewal fails outright (err != nil) and
+			// on the normal path where the remaining lease duration has
+			// dropped under the watcher's renewal threshold (err == nil) -
+			// per vault/api's LifetimeWatcher docs, both cases mean the
+			// caller must re-authenticate to keep working.
+			v.emit(RenewalEvent{Kind: RenewalEventExpired, Source: "token", Err: err})
+
+			reauthed, rerr := v.reauthenticate(ctx)
+			if rerr != nil {
+				v.emit(RenewalEvent{Kind: RenewalEventExpired, Source: "token", Err: rerr})
+				return
+			}
+
+			v.emit(RenewalEvent{Kind: RenewalEventReauthenticated, Source: "token"})
+			go v.watchTokenLoop(ctx, reauthed)
+			return
+		}
+	}
+}
+
+func (v *VaultClient) reauthenticate(ctx context.Context) (*vault.Secret, error) {
+	if v.authenticator == nil {
+		return nil, fmt.Errorf("vault: token expired and no Authenticator is configured to re-authenticate")
+	}
+
+	secret, err := v.authenticator.Login(ctx, v.client)
+	if err != nil {
+		return nil, fmt.Errorf("vault: re-authentication failed: %w", err)
+	}
+
+	v.authSecret = secret
+	return secret, nil
+}
+
+// TrackLease hands a dynamic-secret lease (e.g. a database or PKI
+// credential returned by GetDynamicSecret) to the same renewal machinery
+// used for the login token, so it will be kept alive for as long as the
+// context passed to StartRenewal lives. StartRenewal must be called first.
+func (v *VaultClient) TrackLease(secret *vault.Secret) error {
+	v.initRenewalState()
+
+	if v.renewalCtx == nil {
+		return fmt.Errorf("vault: StartRenewal must be called before TrackLease")
+	}
+	if secret == nil || secret.LeaseID == "" {
+		return fmt.Errorf("vault: secret has no lease to track")
+	}
+	if !secret.Renewable {
+		return fmt.Errorf("vault: lease %s is not renewable", secret.LeaseID)
+	}
+
+	watcher, err := v.client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{Secret: secret})
+	if err != nil {
+		return fmt.Errorf("failed to start lease watcher: %w", err)
+	}
+
+	go watcher.Start()
+	go v.watchLeaseLoop(v.renewalCtx, watcher)
+
+	return nil
+}
+
+func (v *VaultClient) watchLeaseLoop(ctx context.Context, watcher *vault.LifetimeWatcher) {
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-watcher.RenewCh():
+			v.emit(RenewalEvent{Kind: RenewalEventRenewed, Source: "lease"})
+
+		case err := <-watcher.DoneCh():
+			if err != nil {
+				v.emit(RenewalEvent{Kind: RenewalEventExpired, Source: "lease", Err: err})
+			} else {
+				v.emit(RenewalEvent{Kind: RenewalEventExpired, Source: "lease"})
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// vaultRefPrefix is the scheme used by secret references in service
+// manifests, e.g. "vault://secret/data/postgres#password".
+const vaultRefPrefix = "vault://"
+
+// vaultRef is a parsed vault:// secret reference.
+type vaultRef struct {
+	mount   string
+	path    string
+	key     string
+	version kvVersion
+}
+
+// parseVaultRef parses the two reference forms in use across this
+// ecosystem:
+//
+//   - vault://<mount>/data/<path>#<key>   (KV v2, mandatory "data" segment)
+//   - vault:///<mount>/<path>/<key>       (KV v1 path+key form)
+func parseVaultRef(ref string) (vaultRef, error) {
+	if !strings.HasPrefix(ref, vaultRefPrefix) {
+		return vaultRef{}, fmt.Errorf("vault: ref %q does not start with %q", ref, vaultRefPrefix)
+	}
+	rest := strings.TrimPrefix(ref, vaultRefPrefix)
+
+	if hashIdx := strings.IndexByte(rest, '#'); hashIdx >= 0 {
+		pathPart := strings.TrimPrefix(rest[:hashIdx], "/")
+		key := rest[hashIdx+1:]
+
+		parts := strings.SplitN(pathPart, "/", 2)
+		if len(parts) != 2 || parts[1] == "" || key == "" {
+			return vaultRef{}, fmt.Errorf("vault: malformed ref %q, expected vault://<mount>/data/<path>#<key>", ref)
+		}
+
+		subpath := strings.TrimPrefix(parts[1], "data/")
+		if subpath == parts[1] {
+			return vaultRef{}, fmt.Errorf("vault: malformed ref %q, KV v2 refs require a /data/ segment", ref)
+		}
+
+		return vaultRef{mount: parts[0], path: subpath, key: key, version: kvVersion2}, nil
+	}
+
+	segments := strings.Split(strings.TrimPrefix(rest, "/"), "/")
+	if len(segments) < 3 {
+		return vaultRef{}, fmt.Errorf("vault: malformed ref %q, expected vault:///<mount>/<path>/<key>", ref)
+	}
+
+	return vaultRef{
+		mount:   segments[0],
+		path:    strings.Join(segments[1:len(segments)-1], "/"),
+		key:     segments[len(segments)-1],
+		version: kvVersion1,
+	}, nil
+}
+
+// ResolveRef resolves a vault:// secret reference (see parseVaultRef for the
+// two supported forms) to its string value, so service manifests can inline
+// references like DB_PASSWORD: vault://secret/data/postgres#password
+// without each service needing its own Vault plumbing.
+func (v *VaultClient) ResolveRef(ctx context.Context, ref string) (string, error) {
+	parsed, err := parseVaultRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var data map[string]interface{}
+	if parsed.version == kvVersion1 {
+		data, err = v.readKVv1(ctx, parsed.mount, parsed.path)
+	} else {
+		data, err = v.readKVv2(ctx, parsed.mount, parsed.path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return extractSecretValue(parsed, data)
+}
+
+// extractSecretValue pulls parsed.key out of data and asserts it's a
+// string, split out from ResolveRef so the lookup/type-assertion logic is
+// testable without a live Vault server.
+func extractSecretValue(parsed vaultRef, data map[string]interface{}) (string, error) {
+	value, ok := data[parsed.key]
+	if !ok {
+		return "", fmt.Errorf("vault: key %q not found in secret %s/%s", parsed.key, parsed.mount, parsed.path)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault: value for key %q at %s/%s is %T, not a string", parsed.key, parsed.mount, parsed.path, value)
+	}
+
+	return str, nil
+}
+
+// ResolveMap walks a map of config values and rewrites any entry whose
+// value starts with "vault://" to its resolved secret, leaving all other
+// entries untouched. References are resolved concurrently since each one is
+// an independent round-trip to Vault.
+func (v *VaultClient) ResolveMap(ctx context.Context, config map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(config))
+
+	type result struct {
+		key   string
+		value string
+		err   error
+	}
+
+	results := make(chan result, len(config))
+
+	for k, val := range config {
+		if !strings.HasPrefix(val, vaultRefPrefix) {
+			resolved[k] = val
+			continue
+		}
+
+		go func(k, ref string) {
+			value, err := v.ResolveRef(ctx, ref)
+			if err != nil {
+				err = fmt.Errorf("vault: failed to resolve %s for key %q: %w", ref, k, err)
+			}
+			results <- result{key: k, value: value, err: err}
+		}(k, val)
+	}
+
+	pending := len(config) - len(resolved)
+	var firstErr error
+	for i := 0; i < pending; i++ {
+		r := <-results
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		resolved[r.key] = r.value
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVaultRef_KVv2RequiresDataSegment(t *testing.T) {
+	_, err := parseVaultRef("vault://secret/postgres#password")
+	if err == nil {
+		t.Fatal("expected an error for a KV v2 ref missing the /data/ segment, got nil")
+	}
+	if !strings.Contains(err.Error(), "/data/") {
+		t.Fatalf("expected error to mention the missing /data/ segment, got: %v", err)
+	}
+}
+
+func TestParseVaultRef_KVv2(t *testing.T) {
+	ref, err := parseVaultRef("vault://secret/data/postgres#password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := vaultRef{mount: "secret", path: "postgres", key: "password", version: kvVersion2}
+	if ref != want {
+		t.Fatalf("parseVaultRef() = %+v, want %+v", ref, want)
+	}
+}
+
+func TestParseVaultRef_KVv1PathAndKey(t *testing.T) {
+	ref, err := parseVaultRef("vault:///secret/postgres/password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := vaultRef{mount: "secret", path: "postgres", key: "password", version: kvVersion1}
+	if ref != want {
+		t.Fatalf("parseVaultRef() = %+v, want %+v", ref, want)
+	}
+}
+
+func TestExtractSecretValue_MissingKey(t *testing.T) {
+	parsed := vaultRef{mount: "secret", path: "postgres", key: "password"}
+	data := map[string]interface{}{"username": "app"}
+
+	_, err := extractSecretValue(parsed, data)
+	if err == nil {
+		t.Fatal("expected an error for a missing key, got nil")
+	}
+	if !strings.Contains(err.Error(), `"password"`) {
+		t.Fatalf("expected error to name the missing key, got: %v", err)
+	}
+}
+
+func TestExtractSecretValue_NonStringValue(t *testing.T) {
+	parsed := vaultRef{mount: "secret", path: "postgres", key: "port"}
+	data := map[string]interface{}{"port": 5432}
+
+	_, err := extractSecretValue(parsed, data)
+	if err == nil {
+		t.Fatal("expected an error for a non-string value, got nil")
+	}
+	if !strings.Contains(err.Error(), "int") {
+		t.Fatalf("expected error to name the discovered type (int), got: %v", err)
+	}
+}
+
+func TestExtractSecretValue_String(t *testing.T) {
+	parsed := vaultRef{mount: "secret", path: "postgres", key: "password"}
+	data := map[string]interface{}{"password": "hunter2"}
+
+	got, err := extractSecretValue(parsed, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "hunter2" {
+		t.Fatalf("extractSecretValue() = %q, want %q", got, "hunter2")
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// RenderOption configures template rendering.
+type RenderOption func(*template.Template) *template.Template
+
+// WithFuncMap merges extra functions (e.g. sprig.FuncMap()) into the
+// template alongside the Vault functions.
+func WithFuncMap(funcs template.FuncMap) RenderOption {
+	return func(t *template.Template) *template.Template {
+		return t.Funcs(funcs)
+	}
+}
+
+// FuncMap returns a text/template.FuncMap exposing "vault" and "mustVault"
+// template functions backed by this client, so config templates can pull
+// secrets directly: {{ vault "postgres" "password" }}. The path is relative
+// to the client's configured KV mount, as for GetSecretKey.
+//
+// vault returns the provided default (or an empty string) on any error so a
+// template can render best-effort. mustVault propagates the error, causing
+// template.Execute to fail loudly.
+func (v *VaultClient) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"vault":     v.templateVault(context.Background()),
+		"mustVault": v.templateMustVault(context.Background()),
+	}
+}
+
+func (v *VaultClient) templateVault(ctx context.Context) func(path, key string, def ...string) string {
+	return func(path, key string, def ...string) string {
+		value, err := v.GetSecretKey(ctx, path, key)
+		if err != nil {
+			if len(def) > 0 {
+				return def[0]
+			}
+			return ""
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			if len(def) > 0 {
+				return def[0]
+			}
+			return ""
+		}
+
+		return str
+	}
+}
+
+func (v *VaultClient) templateMustVault(ctx context.Context) func(path, key string) (string, error) {
+	return func(path, key string) (string, error) {
+		value, err := v.GetSecretKey(ctx, path, key)
+		if err != nil {
+			return "", err
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("vault: value for key %q at %s is %T, not a string", key, path, value)
+		}
+
+		return str, nil
+	}
+}
+
+// newTemplate builds a text/template.Template named name with this client's
+// vault/mustVault functions installed, plus any caller-supplied funcs from
+// opts layered on top (so a caller-provided "vault" override, if any, wins).
+func (v *VaultClient) newTemplate(ctx context.Context, name string, opts ...RenderOption) *template.Template {
+	t := template.New(name).Funcs(template.FuncMap{
+		"vault":     v.templateVault(ctx),
+		"mustVault": v.templateMustVault(ctx),
+	})
+
+	for _, opt := range opts {
+		t = opt(t)
+	}
+
+	return t
+}
+
+// RenderString renders tmpl with the Vault FuncMap installed and data as the
+// template context.
+func (v *VaultClient) RenderString(ctx context.Context, tmpl string, data any, opts ...RenderOption) (string, error) {
+	t, err := v.newTemplate(ctx, "vault-render", opts...).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("vault: failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("vault: failed to render template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// RenderFile renders the template at srcPath to dstPath with the Vault
+// FuncMap installed. A new dstPath is created with srcPath's permission bits
+// (subject to the umask); an existing dstPath keeps its own mode. This
+// lets services template systemd units, env files, and compose overrides
+// directly from Vault without shelling out to consul-template.
+func (v *VaultClient) RenderFile(ctx context.Context, srcPath, dstPath string, data any, opts ...RenderOption) error {
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return fmt.Errorf("vault: failed to stat template %s: %w", srcPath, err)
+	}
+
+	src, err := os.ReadFile(srcPath)
+	if err != nil {
+		return fmt.Errorf("vault: failed to read template %s: %w", srcPath, err)
+	}
+
+	rendered, err := v.RenderString(ctx, string(src), data, opts...)
+	if err != nil {
+		return fmt.Errorf("vault: failed to render %s: %w", srcPath, err)
+	}
+
+	if err := os.WriteFile(dstPath, []byte(rendered), info.Mode()); err != nil {
+		return fmt.Errorf("vault: failed to write %s: %w", dstPath, err)
+	}
+
+	return nil
+}
@@ -3,17 +3,70 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 
 	vault "github.com/hashicorp/vault/api"
 )
 
+// kvVersion identifies which KV secrets engine version a mount uses.
+type kvVersion int
+
+const (
+	kvVersion2 kvVersion = iota
+	kvVersion1
+)
+
 // VaultClient wraps the Vault API client
 type VaultClient struct {
 	client *vault.Client
+
+	kvMount   string
+	kvVersion kvVersion
+
+	authenticator Authenticator
+	authSecret    *vault.Secret
+
+	renewalOnce      sync.Once
+	renewalEvents    chan RenewalEvent
+	renewalStartOnce sync.Once
+	renewalStartErr  error
+	renewalCtx       context.Context
 }
 
-// NewVaultClient creates a new Vault client
-func NewVaultClient(addr, token string) (*VaultClient, error) {
+// VaultClientOption configures a VaultClient at construction time.
+type VaultClientOption func(*VaultClient)
+
+// WithKVMount configures the KV mount path and engine version (1 or 2) used
+// by GetSecret and GetSecretKey. The default is KV v2 at "secret".
+func WithKVMount(mount string, version int) VaultClientOption {
+	return func(v *VaultClient) {
+		v.kvMount = strings.Trim(mount, "/")
+		if version == 1 {
+			v.kvVersion = kvVersion1
+		} else {
+			v.kvVersion = kvVersion2
+		}
+	}
+}
+
+// WithKVv1 configures the client to talk to a KV v1 mount at the given path.
+func WithKVv1(mount string) VaultClientOption {
+	return WithKVMount(mount, 1)
+}
+
+// NewVaultClient creates a new Vault client authenticated with a static
+// token. It is a thin convenience wrapper over NewVaultClientWithAuth for
+// the common case.
+func NewVaultClient(addr, token string, opts ...VaultClientOption) (*VaultClient, error) {
+	return NewVaultClientWithAuth(addr, TokenAuth(token), opts...)
+}
+
+// NewVaultClientWithAuth creates a new Vault client and logs in using the
+// given Authenticator. By default it talks to a KV v2 mount at "secret";
+// pass WithKVMount or WithKVv1 to point it at a different mount or engine
+// version.
+func NewVaultClientWithAuth(addr string, auth Authenticator, opts ...VaultClientOption) (*VaultClient, error) {
 	config := vault.DefaultConfig()
 	config.Address = addr
 
@@ -22,20 +75,59 @@ func NewVaultClient(addr, token string) (*VaultClient, error) {
 		return nil, fmt.Errorf("failed to create Vault client: %w", err)
 	}
 
-	client.SetToken(token)
+	v := &VaultClient{
+		client:    client,
+		kvMount:   "secret",
+		kvVersion: kvVersion2,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	secret, err := auth.Login(context.Background(), client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate to Vault: %w", err)
+	}
+
+	v.authenticator = auth
+	v.authSecret = secret
 
-	return &VaultClient{client: client}, nil
+	return v, nil
 }
 
-// GetSecret retrieves a secret from Vault KV v2
+// GetSecret retrieves a secret from the configured KV mount, transparently
+// handling both KV v1 and KV v2 engines.
 func (v *VaultClient) GetSecret(ctx context.Context, path string) (map[string]interface{}, error) {
-	secret, err := v.client.KVv2("secret").Get(ctx, path)
+	if v.kvVersion == kvVersion1 {
+		return v.readKVv1(ctx, v.kvMount, path)
+	}
+	return v.readKVv2(ctx, v.kvMount, path)
+}
+
+// readKVv2 reads a path from an arbitrary KV v2 mount.
+func (v *VaultClient) readKVv2(ctx context.Context, mount, path string) (map[string]interface{}, error) {
+	secret, err := v.client.KVv2(mount).Get(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read secret at %s: %w", path, err)
+		return nil, fmt.Errorf("failed to read secret at %s/%s: %w", mount, path, err)
 	}
 
 	if secret == nil || secret.Data == nil {
-		return nil, fmt.Errorf("no data found at %s", path)
+		return nil, fmt.Errorf("no data found at %s/%s", mount, path)
+	}
+
+	return secret.Data, nil
+}
+
+// readKVv1 reads a path from an arbitrary KV v1 mount.
+func (v *VaultClient) readKVv1(ctx context.Context, mount, path string) (map[string]interface{}, error) {
+	secret, err := v.client.Logical().ReadWithContext(ctx, mount+"/"+path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret at %s/%s: %w", mount, path, err)
+	}
+
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no data found at %s/%s", mount, path)
 	}
 
 	return secret.Data, nil
@@ -56,17 +148,74 @@ func (v *VaultClient) GetSecretKey(ctx context.Context, path, key string) (inter
 	return value, nil
 }
 
-// HealthCheck checks if Vault is accessible and unsealed
+// GetDynamicSecret reads path (e.g. a database or PKI credentials endpoint)
+// and returns the raw *vault.Secret, lease metadata included, so the caller
+// can hand it to TrackLease. Unlike GetSecret, this does not go through the
+// KVv1/KVv2 helpers: dynamic secrets engines aren't KV mounts and their
+// lease is the whole point of the read.
+func (v *VaultClient) GetDynamicSecret(ctx context.Context, path string) (*vault.Secret, error) {
+	secret, err := v.client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic secret at %s: %w", path, err)
+	}
+
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no data found at %s", path)
+	}
+
+	return secret, nil
+}
+
+// HealthCheck checks if Vault is accessible and unsealed, and verifies that
+// the configured KV mount actually matches the configured engine version so
+// misconfiguration (e.g. pointing a KV v2 client at a v1 mount) surfaces early.
 func (v *VaultClient) HealthCheck(ctx context.Context) (map[string]interface{}, error) {
-	health, err := v.client.Sys().Health()
+	health, err := v.client.Sys().HealthWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check Vault health: %w", err)
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"initialized": health.Initialized,
 		"sealed":      health.Sealed,
 		"standby":     health.Standby,
 		"version":     health.Version,
-	}, nil
+	}
+
+	if detected, err := v.detectMountVersion(ctx); err != nil {
+		result["kv_mount_error"] = err.Error()
+	} else if detected != v.kvVersion {
+		result["kv_mount_error"] = fmt.Sprintf(
+			"configured KV v%d for mount %q but Vault reports v%d",
+			kvVersionNumber(v.kvVersion), v.kvMount, kvVersionNumber(detected),
+		)
+	}
+
+	return result, nil
+}
+
+// detectMountVersion inspects sys/mounts to determine which KV engine
+// version backs the configured mount.
+func (v *VaultClient) detectMountVersion(ctx context.Context) (kvVersion, error) {
+	mounts, err := v.client.Sys().ListMountsWithContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list mounts: %w", err)
+	}
+
+	mount, ok := mounts[v.kvMount+"/"]
+	if !ok {
+		return 0, fmt.Errorf("mount %q not found", v.kvMount)
+	}
+
+	if mount.Options["version"] == "2" {
+		return kvVersion2, nil
+	}
+	return kvVersion1, nil
+}
+
+func kvVersionNumber(k kvVersion) int {
+	if k == kvVersion1 {
+		return 1
+	}
+	return 2
 }